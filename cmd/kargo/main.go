@@ -0,0 +1,61 @@
+// Command kargo discovers installed WASM plugins, registers each as a cobra
+// subcommand, and wires up the `kargo plugin` management commands
+// (install, push, list, enable/disable, reload) over pkg/wasmhost,
+// pkg/hotplugin, and pkg/pluginregistry.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cyrup-ai/kargo/pkg/hotplugin"
+	"github.com/cyrup-ai/kargo/pkg/plugincmd"
+	"github.com/cyrup-ai/kargo/pkg/pluginregistry"
+	"github.com/cyrup-ai/kargo/pkg/wasmhost"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return fmt.Errorf("kargo: resolve config dir: %w", err)
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return fmt.Errorf("kargo: resolve cache dir: %w", err)
+	}
+
+	pluginDir := filepath.Join(configDir, "kargo", "plugins")
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		return fmt.Errorf("kargo: create plugin dir: %w", err)
+	}
+
+	reg := wasmhost.NewRegistry(pluginDir)
+	for _, loadErr := range reg.Discover() {
+		fmt.Fprintln(os.Stderr, "kargo: warning:", loadErr)
+	}
+
+	installer, err := pluginregistry.NewInstaller(filepath.Join(cacheDir, "kargo", "plugins"), pluginDir)
+	if err != nil {
+		return fmt.Errorf("kargo: create installer: %w", err)
+	}
+	watcher := hotplugin.NewWatcher(reg)
+
+	root := &cobra.Command{
+		Use:   "kargo",
+		Short: "kargo manages and runs WASM plugins",
+	}
+	root.AddCommand(plugincmd.NewPluginCommand(reg, watcher, installer))
+	plugincmd.AddPluginCommands(root, reg)
+
+	return root.Execute()
+}
@@ -0,0 +1,141 @@
+package hotplugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+
+	"github.com/cyrup-ai/kargo/pkg/wasmhost"
+)
+
+// writeFixturePlugin compiles a minimal WAT module implementing just enough
+// of the plugin ABI for wasmhost.Registry to load and reload it. This is a
+// deliberate duplicate of pkg/wasmhost's own test helper rather than an
+// exported one, since test helpers aren't part of either package's public
+// API.
+func writeFixturePlugin(t *testing.T, path, commandJSON, metadataJSON, resultJSON string) {
+	t.Helper()
+
+	wat := fmt.Sprintf(`(module
+  (import "kargo" "kargo_emit" (func $kargo_emit (param i32 i32)))
+  (import "kargo" "kargo_should_cancel" (func $kargo_should_cancel (result i32)))
+  (memory (export "memory") 2)
+  (global $last_len (mut i32) (i32.const 0))
+  (data (i32.const 0) %s)
+  (data (i32.const 1024) %s)
+  (data (i32.const 2048) %s)
+  (func (export "get_command") (result i32)
+    i32.const %d
+    global.set $last_len
+    i32.const 0)
+  (func (export "get_metadata") (result i32)
+    i32.const %d
+    global.set $last_len
+    i32.const 1024)
+  (func (export "get_last_result_len") (result i32)
+    global.get $last_len)
+  (func (export "execute") (param i32 i32) (result i32)
+    i32.const %d
+    global.set $last_len
+    i32.const 2048)
+  (func (export "malloc") (param i32) (result i32)
+    i32.const 4096)
+  (func (export "free") (param i32))
+)`,
+		watString(commandJSON), watString(metadataJSON), watString(resultJSON),
+		len(commandJSON), len(metadataJSON), len(resultJSON),
+	)
+
+	wasm, err := wasmtime.Wat2Wasm(wat)
+	if err != nil {
+		t.Fatalf("compile fixture WAT: %v\n%s", err, wat)
+	}
+	if err := os.WriteFile(path, wasm, 0o644); err != nil {
+		t.Fatalf("write fixture %s: %v", path, err)
+	}
+}
+
+func watString(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	return `"` + s + `"`
+}
+
+func TestWatcherReloadDelegatesToRegistry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greeter.wasm")
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"v1","args":[]}`,
+		`{"name":"greeter","version":"1.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true}`,
+	)
+
+	reg := wasmhost.NewRegistry(dir)
+	if errs := reg.Discover(); len(errs) > 0 {
+		t.Fatalf("Discover returned errors: %v", errs)
+	}
+	w := NewWatcher(reg)
+
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"v2","args":[]}`,
+		`{"name":"greeter","version":"2.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true}`,
+	)
+
+	if err := w.Reload("greeter"); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	e, ok := reg.Get("greeter")
+	if !ok {
+		t.Fatal("Get(\"greeter\") not found")
+	}
+	if e.Command.About != "v2" {
+		t.Errorf("Command.About = %q, want v2", e.Command.About)
+	}
+}
+
+func TestWatcherWatchDirDiscoversNewPlugin(t *testing.T) {
+	dir := t.TempDir()
+	reg := wasmhost.NewRegistry(dir)
+	w := NewWatcher(reg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- w.WatchDir(ctx) }()
+
+	// Give the fsnotify watcher time to start before the write it needs to
+	// observe happens.
+	time.Sleep(100 * time.Millisecond)
+
+	writeFixturePlugin(t, filepath.Join(dir, "greeter.wasm"),
+		`{"name":"greet","about":"v1","args":[]}`,
+		`{"name":"greeter","version":"1.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true}`,
+	)
+
+	deadline := time.After(5 * time.Second)
+	for {
+		if _, ok := reg.Get("greeter"); ok {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("WatchDir did not discover the new plugin within the deadline")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchDir returned error: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package hotplugin watches a plugin directory for changes and swaps
+// running WASM plugin instances in place, without restarting kargo. It is a
+// thin driver over wasmhost.Registry's Reload/ReloadAll, which do the actual
+// compile-verify-swap work.
+package hotplugin
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/cyrup-ai/kargo/pkg/wasmhost"
+)
+
+// debounce coalesces the burst of write events a single plugin rebuild
+// typically produces into one reload.
+const debounce = 250 * time.Millisecond
+
+// Watcher drives hot-reloads of a wasmhost.Registry in response to
+// filesystem changes in its plugin directory.
+type Watcher struct {
+	Registry *wasmhost.Registry
+}
+
+// NewWatcher creates a Watcher over reg.
+func NewWatcher(reg *wasmhost.Registry) *Watcher {
+	return &Watcher{Registry: reg}
+}
+
+// Reload recompiles and swaps in the single plugin named name. See
+// wasmhost.Registry.Reload for the atomicity and failure-handling
+// guarantees.
+func (w *Watcher) Reload(name string) error {
+	return w.Registry.Reload(name)
+}
+
+// ReloadAll reloads every currently registered plugin.
+func (w *Watcher) ReloadAll() []error {
+	return w.Registry.ReloadAll()
+}
+
+// WatchDir watches the registry's plugin directory for .wasm file changes
+// and reloads (or, for a new file, newly discovers) the affected plugin. It
+// blocks until ctx is canceled or the underlying fsnotify watcher fails to
+// start.
+func (w *Watcher) WatchDir(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("hotplugin: create watcher: %w", err)
+	}
+	defer fsw.Close()
+
+	if err := fsw.Add(w.Registry.Dir); err != nil {
+		return fmt.Errorf("hotplugin: watch %s: %w", w.Registry.Dir, err)
+	}
+
+	pending := make(map[string]*time.Timer)
+	defer func() {
+		for _, t := range pending {
+			t.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Ext(ev.Name) != ".wasm" {
+				continue
+			}
+			path := ev.Name
+			if t, exists := pending[path]; exists {
+				t.Stop()
+			}
+			pending[path] = time.AfterFunc(debounce, func() { w.handleChange(path) })
+		case _, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			// fsnotify errors aren't tied to a specific plugin; the next
+			// reload attempt will surface a fresh error via
+			// Entry.LastReloadError if the problem persists.
+		}
+	}
+}
+
+func (w *Watcher) handleChange(path string) {
+	if name, ok := w.Registry.NameForPath(path); ok {
+		_ = w.Registry.Reload(name)
+		return
+	}
+	// Unrecognized path: treat it as a newly installed plugin.
+	w.Registry.Discover()
+}
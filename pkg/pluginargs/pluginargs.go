@@ -0,0 +1,207 @@
+// Package pluginargs parses and validates plugin command arguments against
+// an ArgDefinition list. It has no dependency on wasmtime or cobra, which
+// keeps it buildable with TinyGo: the guest-side plugin template imports it
+// to parse its own args map, and the host imports it to reject bad
+// arguments before ever calling into WASM.
+package pluginargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Supported ArgDefinition.Type values.
+const (
+	TypeString      = "string"
+	TypeInt         = "int"
+	TypeFloat       = "float"
+	TypeBool        = "bool"
+	TypeStringSlice = "stringSlice"
+	TypePath        = "path"
+	TypeDuration    = "duration"
+)
+
+// ArgDefinition describes one argument of a plugin command. The guest
+// template's CommandDefinition uses this type directly; the host's
+// wasmhost.ArgDefinition has the same fields and is converted to this type
+// before validation.
+type ArgDefinition struct {
+	Name       string   `json:"name"`
+	Short      *string  `json:"short,omitempty"`
+	Long       *string  `json:"long,omitempty"`
+	Help       string   `json:"help"`
+	Required   bool     `json:"required"`
+	TakesValue bool     `json:"takesValue"`
+	Type       string   `json:"type,omitempty"`
+	Default    string   `json:"default,omitempty"`
+	Choices    []string `json:"choices,omitempty"`
+}
+
+// ArgError reports a single argument that failed validation or coercion.
+type ArgError struct {
+	Arg    string
+	Reason string
+}
+
+func (e *ArgError) Error() string {
+	return fmt.Sprintf("argument %q: %s", e.Arg, e.Reason)
+}
+
+// Parse validates raw against defs and returns a new map with defaults
+// applied and every present value coerced to its declared Type. It enforces
+// Required and, for string-typed args, Choices. The first invalid or
+// missing-required argument is returned as an *ArgError.
+func Parse(defs []ArgDefinition, raw map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(defs))
+
+	for _, def := range defs {
+		v, present := raw[def.Name]
+		if !present {
+			if def.Required {
+				return nil, &ArgError{Arg: def.Name, Reason: "required argument missing"}
+			}
+			if def.Default == "" {
+				continue
+			}
+			v = def.Default
+		}
+
+		coerced, err := coerce(def, v)
+		if err != nil {
+			return nil, &ArgError{Arg: def.Name, Reason: err.Error()}
+		}
+
+		if len(def.Choices) > 0 {
+			if err := checkChoice(def, coerced); err != nil {
+				return nil, &ArgError{Arg: def.Name, Reason: err.Error()}
+			}
+		}
+
+		out[def.Name] = coerced
+	}
+
+	return out, nil
+}
+
+// Decode validates raw against defs (as Parse does) and unmarshals the
+// result into dst, a pointer to a struct whose json tags match the
+// ArgDefinition names.
+func Decode(defs []ArgDefinition, raw map[string]interface{}, dst interface{}) error {
+	parsed, err := Parse(defs, raw)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(parsed)
+	if err != nil {
+		return fmt.Errorf("pluginargs: re-encode parsed args: %w", err)
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		return fmt.Errorf("pluginargs: decode args into %T: %w", dst, err)
+	}
+	return nil
+}
+
+func coerce(def ArgDefinition, v interface{}) (interface{}, error) {
+	switch def.Type {
+	case "", TypeString, TypePath:
+		s, ok := asString(v)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %T", v)
+		}
+		return s, nil
+
+	case TypeInt:
+		switch n := v.(type) {
+		case float64:
+			return int(n), nil
+		case string:
+			i, err := strconv.Atoi(n)
+			if err != nil {
+				return nil, fmt.Errorf("expected an int: %w", err)
+			}
+			return i, nil
+		default:
+			return nil, fmt.Errorf("expected an int, got %T", v)
+		}
+
+	case TypeFloat:
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case string:
+			f, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return nil, fmt.Errorf("expected a float: %w", err)
+			}
+			return f, nil
+		default:
+			return nil, fmt.Errorf("expected a float, got %T", v)
+		}
+
+	case TypeBool:
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			parsed, err := strconv.ParseBool(b)
+			if err != nil {
+				return nil, fmt.Errorf("expected a bool: %w", err)
+			}
+			return parsed, nil
+		default:
+			return nil, fmt.Errorf("expected a bool, got %T", v)
+		}
+
+	case TypeStringSlice:
+		switch s := v.(type) {
+		case []interface{}:
+			out := make([]string, 0, len(s))
+			for _, e := range s {
+				str, ok := asString(e)
+				if !ok {
+					return nil, fmt.Errorf("expected a list of strings")
+				}
+				out = append(out, str)
+			}
+			return out, nil
+		case []string:
+			return s, nil
+		default:
+			return nil, fmt.Errorf("expected a list of strings, got %T", v)
+		}
+
+	case TypeDuration:
+		s, ok := asString(v)
+		if !ok {
+			return nil, fmt.Errorf("expected a duration string, got %T", v)
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected a duration: %w", err)
+		}
+		return d.String(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown arg type %q", def.Type)
+	}
+}
+
+func checkChoice(def ArgDefinition, v interface{}) error {
+	s, ok := asString(v)
+	if !ok {
+		return nil // Choices only constrains string-like values.
+	}
+	for _, c := range def.Choices {
+		if c == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of %v", s, def.Choices)
+}
+
+func asString(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
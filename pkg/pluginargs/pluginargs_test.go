@@ -0,0 +1,139 @@
+package pluginargs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCoercion(t *testing.T) {
+	tests := []struct {
+		name string
+		defs []ArgDefinition
+		raw  map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "string default applied when absent",
+			defs: []ArgDefinition{{Name: "input", Type: TypeString, Default: "default"}},
+			raw:  map[string]interface{}{},
+			want: map[string]interface{}{"input": "default"},
+		},
+		{
+			name: "int coerced from float64 (JSON number)",
+			defs: []ArgDefinition{{Name: "count", Type: TypeInt}},
+			raw:  map[string]interface{}{"count": float64(3)},
+			want: map[string]interface{}{"count": 3},
+		},
+		{
+			name: "int coerced from string",
+			defs: []ArgDefinition{{Name: "count", Type: TypeInt}},
+			raw:  map[string]interface{}{"count": "3"},
+			want: map[string]interface{}{"count": 3},
+		},
+		{
+			name: "float coerced from string",
+			defs: []ArgDefinition{{Name: "ratio", Type: TypeFloat}},
+			raw:  map[string]interface{}{"ratio": "1.5"},
+			want: map[string]interface{}{"ratio": 1.5},
+		},
+		{
+			name: "bool coerced from string",
+			defs: []ArgDefinition{{Name: "verbose", Type: TypeBool}},
+			raw:  map[string]interface{}{"verbose": "true"},
+			want: map[string]interface{}{"verbose": true},
+		},
+		{
+			name: "stringSlice coerced from []interface{}",
+			defs: []ArgDefinition{{Name: "tags", Type: TypeStringSlice}},
+			raw:  map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			want: map[string]interface{}{"tags": []string{"a", "b"}},
+		},
+		{
+			name: "duration normalized via time.ParseDuration",
+			defs: []ArgDefinition{{Name: "timeout", Type: TypeDuration}},
+			raw:  map[string]interface{}{"timeout": "1h30m"},
+			want: map[string]interface{}{"timeout": "1h30m0s"},
+		},
+		{
+			name: "missing optional arg without default is omitted",
+			defs: []ArgDefinition{{Name: "input", Type: TypeString}},
+			raw:  map[string]interface{}{},
+			want: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.defs, tt.raw)
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequired(t *testing.T) {
+	defs := []ArgDefinition{{Name: "input", Type: TypeString, Required: true}}
+
+	if _, err := Parse(defs, map[string]interface{}{}); err == nil {
+		t.Fatal("expected error for missing required argument, got nil")
+	}
+
+	got, err := Parse(defs, map[string]interface{}{"input": "value"})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got["input"] != "value" {
+		t.Errorf("Parse()[\"input\"] = %v, want %q", got["input"], "value")
+	}
+}
+
+func TestParseChoices(t *testing.T) {
+	defs := []ArgDefinition{{
+		Name:    "level",
+		Type:    TypeString,
+		Choices: []string{"low", "medium", "high"},
+	}}
+
+	if _, err := Parse(defs, map[string]interface{}{"level": "extreme"}); err == nil {
+		t.Fatal("expected error for value not in Choices, got nil")
+	}
+
+	got, err := Parse(defs, map[string]interface{}{"level": "medium"})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got["level"] != "medium" {
+		t.Errorf("Parse()[\"level\"] = %v, want %q", got["level"], "medium")
+	}
+}
+
+func TestParseTypeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		def  ArgDefinition
+		v    interface{}
+	}{
+		{"bad int", ArgDefinition{Name: "n", Type: TypeInt}, "not-a-number"},
+		{"bad float", ArgDefinition{Name: "n", Type: TypeFloat}, "not-a-number"},
+		{"bad bool", ArgDefinition{Name: "n", Type: TypeBool}, "not-a-bool"},
+		{"bad duration", ArgDefinition{Name: "n", Type: TypeDuration}, "not-a-duration"},
+		{"bad stringSlice element", ArgDefinition{Name: "n", Type: TypeStringSlice}, []interface{}{1, 2}},
+		{"unknown type", ArgDefinition{Name: "n", Type: "bogus"}, "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]ArgDefinition{tt.def}, map[string]interface{}{"n": tt.v})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if _, ok := err.(*ArgError); !ok {
+				t.Errorf("expected *ArgError, got %T", err)
+			}
+		})
+	}
+}
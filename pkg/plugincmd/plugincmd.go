@@ -0,0 +1,361 @@
+// Package plugincmd builds the `kargo plugin` cobra command tree: a
+// Docker-style management tree (list, inspect, enable, disable, remove) over
+// a pkg/wasmhost.Registry, plus a dynamically generated subcommand per
+// enabled plugin so that `kargo <plugin-name>` dispatches straight to
+// Execute.
+package plugincmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cyrup-ai/kargo/pkg/hotplugin"
+	"github.com/cyrup-ai/kargo/pkg/pluginargs"
+	"github.com/cyrup-ai/kargo/pkg/pluginmanifest"
+	"github.com/cyrup-ai/kargo/pkg/pluginregistry"
+	"github.com/cyrup-ai/kargo/pkg/wasmhost"
+)
+
+// defaultPluginTimeout bounds how long a single plugin invocation may run
+// before its kargo_should_cancel flag is set. TODO: source this from the
+// plugin's manifest/settings instead of a single global default.
+const defaultPluginTimeout = 30 * time.Second
+
+// NewPluginCommand builds the `kargo plugin` management command. It does not
+// add a subcommand per plugin; AddPluginCommands does that on the root
+// command. watcher backs the reload and watch subcommands (pass
+// hotplugin.NewWatcher(reg)); watch runs Watcher.WatchDir in the foreground
+// and reloads plugins only in that process's own registry. installer backs
+// install/push (pass pluginregistry.NewInstaller(cacheDir, reg.Dir)).
+func NewPluginCommand(reg *wasmhost.Registry, watcher *hotplugin.Watcher, installer *pluginregistry.Installer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage kargo WASM plugins",
+	}
+
+	cmd.AddCommand(
+		newPluginListCommand(reg),
+		newPluginInspectCommand(reg),
+		newPluginEnableCommand(reg),
+		newPluginDisableCommand(reg),
+		newPluginRemoveCommand(reg),
+		newPluginReloadCommand(watcher),
+		newPluginWatchCommand(watcher),
+		newPluginInstallCommand(installer),
+		newPluginPushCommand(installer),
+	)
+
+	return cmd
+}
+
+func newPluginListCommand(reg *wasmhost.Registry) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List installed plugins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, e := range reg.List() {
+				status := "enabled"
+				if !e.Enabled {
+					status = "disabled"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", e.Metadata.Name, e.Metadata.Version, status)
+			}
+			return nil
+		},
+	}
+}
+
+func newPluginInspectCommand(reg *wasmhost.Registry) *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect <name>",
+		Short: "Show metadata and the command definition for a plugin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			e, ok := reg.Get(args[0])
+			if !ok {
+				return fmt.Errorf("no such plugin %q", args[0])
+			}
+			out, err := json.MarshalIndent(struct {
+				Metadata *wasmhost.PluginMetadata    `json:"metadata"`
+				Command  *wasmhost.CommandDefinition `json:"command"`
+				Enabled  bool                        `json:"enabled"`
+			}{e.Metadata, e.Command, e.Enabled}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+}
+
+func newPluginEnableCommand(reg *wasmhost.Registry) *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable <name>",
+		Short: "Enable a disabled plugin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reg.SetEnabled(args[0], true)
+		},
+	}
+}
+
+func newPluginDisableCommand(reg *wasmhost.Registry) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable <name>",
+		Short: "Disable a plugin without uninstalling it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reg.SetEnabled(args[0], false)
+		},
+	}
+}
+
+func newPluginRemoveCommand(reg *wasmhost.Registry) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Uninstall a plugin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reg.Remove(args[0])
+		},
+	}
+}
+
+func newPluginInstallCommand(installer *pluginregistry.Installer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "install <host>/<owner>/<repo>#<subpath>@<ref>",
+		Short: "Build and install a plugin from a git-hosted template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := installer.Install(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			return nil
+		},
+	}
+}
+
+func newPluginPushCommand(installer *pluginregistry.Installer) *cobra.Command {
+	var fromDir string
+	cmd := &cobra.Command{
+		Use:   "push <host>/<owner>/<repo>#<subpath>@<ref>",
+		Short: "Publish a plugin's sources to a git-hosted registry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return installer.Push(args[0], fromDir)
+		},
+	}
+	cmd.Flags().StringVar(&fromDir, "from", ".", "local directory containing the plugin sources to push")
+	return cmd
+}
+
+func newPluginReloadCommand(watcher *hotplugin.Watcher) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload [name]",
+		Short: "Recompile and hot-swap a plugin (or all plugins, if no name is given)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				if errs := watcher.ReloadAll(); len(errs) > 0 {
+					return fmt.Errorf("%d plugin(s) failed to reload: %w", len(errs), errs[0])
+				}
+				return nil
+			}
+			return watcher.Reload(args[0])
+		},
+	}
+}
+
+func newPluginWatchCommand(watcher *hotplugin.Watcher) *cobra.Command {
+	return &cobra.Command{
+		Use:   "watch",
+		Short: "Watch the plugin directory and hot-reload plugins as their .wasm files change",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return watcher.WatchDir(cmd.Context())
+		},
+	}
+}
+
+// AddPluginCommands registers a top-level cobra command for each enabled
+// plugin in reg, so that `kargo <plugin-name> [flags]` dispatches to that
+// plugin's Execute export. Call this after Registry.Discover, and again
+// after any reload that may have changed the set of enabled plugins.
+func AddPluginCommands(root *cobra.Command, reg *wasmhost.Registry) {
+	for _, e := range reg.List() {
+		if !e.Enabled {
+			continue
+		}
+		root.AddCommand(newPluginInvokeCommand(e))
+	}
+}
+
+// resolvedSettings loads the plugin.yaml next to e's .wasm file, if any, and
+// resolves its declared settings against the on-disk config store. A plugin
+// without a manifest simply contributes no settings; any other failure (a
+// malformed manifest, an unwritable config store, a setting that fails
+// validation) is returned to the caller rather than silently dropped, since
+// invoking the plugin with the wrong settings silently is worse than failing
+// the command.
+func resolvedSettings(e *wasmhost.Entry) (map[string]string, error) {
+	manifest, err := pluginmanifest.Load(e.Plugin.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("loading plugin manifest: %w", err)
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating plugin config dir: %w", err)
+	}
+	store, err := pluginmanifest.NewFileStore(filepath.Join(configDir, "kargo", "plugin-settings.json"))
+	if err != nil {
+		return nil, fmt.Errorf("opening plugin settings store: %w", err)
+	}
+
+	settings, err := manifest.Resolve(store)
+	if err != nil {
+		return nil, fmt.Errorf("resolving plugin settings: %w", err)
+	}
+	return settings, nil
+}
+
+// toArgDefs converts a plugin's reported ArgDefinitions to the shape
+// pkg/pluginargs validates against.
+func toArgDefs(defs []wasmhost.ArgDefinition) []pluginargs.ArgDefinition {
+	out := make([]pluginargs.ArgDefinition, len(defs))
+	for i, d := range defs {
+		out[i] = pluginargs.ArgDefinition{
+			Name:       d.Name,
+			Short:      d.Short,
+			Long:       d.Long,
+			Help:       d.Help,
+			Required:   d.Required,
+			TakesValue: d.TakesValue,
+			Type:       d.Type,
+			Default:    d.Default,
+			Choices:    d.Choices,
+		}
+	}
+	return out
+}
+
+// buildExecuteArgs validates argsMap (built from CLI flags) against defs and
+// then merges settings (resolved plugin settings, see resolvedSettings) into
+// the validated result. settings is merged in after validation, not before,
+// because pluginargs.Parse only ever emits keys present in defs and would
+// otherwise silently drop every resolved setting before it reaches Execute.
+func buildExecuteArgs(defs []pluginargs.ArgDefinition, argsMap map[string]interface{}, settings map[string]string) (map[string]interface{}, error) {
+	validated, err := pluginargs.Parse(defs, argsMap)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range settings {
+		validated[k] = v
+	}
+	return validated, nil
+}
+
+// newEventPrinter builds the onEvent callback passed to Entry.Execute: in
+// "json" mode every event is written as one JSON line, and in "text" mode
+// log/progress/result events get a short human-readable rendering.
+func newEventPrinter(cmd *cobra.Command, outputMode string) func(wasmhost.StreamEvent) {
+	return func(ev wasmhost.StreamEvent) {
+		if outputMode == "json" {
+			b, err := json.Marshal(ev)
+			if err == nil {
+				fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			}
+			return
+		}
+
+		switch ev.Kind {
+		case "log":
+			fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", ev.Level, ev.Msg)
+		case "progress":
+			fmt.Fprintf(cmd.OutOrStdout(), "progress: %d%%\n", ev.Pct)
+		case "result":
+			if ev.Output != nil {
+				fmt.Fprintln(cmd.OutOrStdout(), *ev.Output)
+			}
+		}
+	}
+}
+
+func newPluginInvokeCommand(e *wasmhost.Entry) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   e.Command.Name,
+		Short: e.Command.About,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			argsMap := make(map[string]interface{})
+			for _, a := range e.Command.Args {
+				if a.TakesValue {
+					if v, err := cmd.Flags().GetString(a.Name); err == nil && v != "" {
+						argsMap[a.Name] = v
+					}
+				} else if v, err := cmd.Flags().GetBool(a.Name); err == nil {
+					argsMap[a.Name] = v
+				}
+			}
+
+			settings, err := resolvedSettings(e)
+			if err != nil {
+				return err
+			}
+
+			validated, err := buildExecuteArgs(toArgDefs(e.Command.Args), argsMap, settings)
+			if err != nil {
+				return err
+			}
+
+			outputMode, _ := cmd.Flags().GetString("output")
+			onEvent := newEventPrinter(cmd, outputMode)
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), defaultPluginTimeout)
+			defer cancel()
+
+			result, err := e.Execute(ctx, validated, onEvent)
+			if err != nil {
+				return err
+			}
+			if !result.Success {
+				msg := "plugin execution failed"
+				if result.Error != nil {
+					msg = *result.Error
+				}
+				return fmt.Errorf("%s", msg)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("output", "text", `output format for streamed events: "text" or "json"`)
+
+	for _, a := range e.Command.Args {
+		name, short := a.Name, ""
+		if a.Short != nil {
+			short = *a.Short
+		}
+		if a.TakesValue {
+			cmd.Flags().StringP(name, short, "", a.Help)
+		} else {
+			cmd.Flags().BoolP(name, short, false, a.Help)
+		}
+		if a.Required {
+			_ = cmd.MarkFlagRequired(name)
+		}
+	}
+
+	return cmd
+}
@@ -0,0 +1,43 @@
+package plugincmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cyrup-ai/kargo/pkg/pluginargs"
+)
+
+func TestBuildExecuteArgsMergesSettingsAfterValidation(t *testing.T) {
+	defs := []pluginargs.ArgDefinition{
+		{Name: "path", Required: true, TakesValue: true, Type: pluginargs.TypeString},
+	}
+	argsMap := map[string]interface{}{"path": "./src"}
+	settings := map[string]string{"api-token": "secret-value"}
+
+	got, err := buildExecuteArgs(defs, argsMap, settings)
+	if err != nil {
+		t.Fatalf("buildExecuteArgs returned error: %v", err)
+	}
+
+	if got["path"] != "./src" {
+		t.Errorf("path = %v, want %q", got["path"], "./src")
+	}
+	if got["api-token"] != "secret-value" {
+		t.Errorf("api-token = %v, want %q (resolved settings must reach Execute)", got["api-token"], "secret-value")
+	}
+}
+
+func TestBuildExecuteArgsPropagatesValidationErrors(t *testing.T) {
+	defs := []pluginargs.ArgDefinition{
+		{Name: "path", Required: true, TakesValue: true, Type: pluginargs.TypeString},
+	}
+
+	_, err := buildExecuteArgs(defs, map[string]interface{}{}, map[string]string{"api-token": "secret-value"})
+	if err == nil {
+		t.Fatal("expected an error for a missing required argument, got nil")
+	}
+	var argErr *pluginargs.ArgError
+	if !errors.As(err, &argErr) {
+		t.Errorf("error = %v, want an *pluginargs.ArgError", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Package pluginmanifest parses and validates the plugin.yaml manifest that
+// sits next to a plugin's .wasm file, and resolves its declared settings
+// (pkg/wasmhost.PluginSetting) against user-configured values before they're
+// injected into the args JSON passed to a plugin's execute export.
+package pluginmanifest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cyrup-ai/kargo/pkg/wasmhost"
+)
+
+// Manifest is the parsed contents of a plugin.yaml.
+type Manifest struct {
+	Name     string                   `yaml:"name"`
+	Version  string                   `yaml:"version"`
+	Settings []wasmhost.PluginSetting `yaml:"settings"`
+}
+
+// knownTypes are the setting types recognized by the host, matching the
+// PluginSetting.Type values the template's ABI can encode.
+var knownTypes = map[string]bool{
+	"bool":      true,
+	"text":      true,
+	"dropdown":  true,
+	"radio":     true,
+	"generated": true,
+	"number":    true,
+	"username":  true,
+}
+
+// Load reads and validates the plugin.yaml next to wasmPath (same directory,
+// filename "plugin.yaml").
+func Load(wasmPath string) (*Manifest, error) {
+	path := filepath.Join(filepath.Dir(wasmPath), "plugin.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pluginmanifest: read %s: %w", path, err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("pluginmanifest: parse %s: %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("pluginmanifest: %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Validate checks every declared setting has a known type, a non-empty key,
+// and that dropdown/radio settings declare at least one option.
+func (m *Manifest) Validate() error {
+	seen := make(map[string]bool, len(m.Settings))
+	for _, s := range m.Settings {
+		if s.Key == "" {
+			return fmt.Errorf("setting with empty key")
+		}
+		if seen[s.Key] {
+			return fmt.Errorf("duplicate setting key %q", s.Key)
+		}
+		seen[s.Key] = true
+
+		if !knownTypes[s.Type] {
+			return fmt.Errorf("setting %q: unknown type %q", s.Key, s.Type)
+		}
+		if (s.Type == "dropdown" || s.Type == "radio") && len(s.Options) == 0 {
+			return fmt.Errorf("setting %q: type %q requires at least one option", s.Key, s.Type)
+		}
+	}
+	return nil
+}
+
+// ConfigStore persists resolved setting values across invocations, keyed by
+// plugin name then setting key.
+type ConfigStore interface {
+	Get(pluginName, key string) (string, bool)
+	Set(pluginName, key, value string) error
+}
+
+// Resolve returns the value for each declared setting: the ConfigStore's
+// persisted value if present, otherwise the setting's Default, generating a
+// random value (and persisting it) for "generated" settings that have no
+// stored value yet.
+func (m *Manifest) Resolve(store ConfigStore) (map[string]string, error) {
+	out := make(map[string]string, len(m.Settings))
+	for _, s := range m.Settings {
+		if v, ok := store.Get(m.Name, s.Key); ok {
+			out[s.Key] = v
+			continue
+		}
+
+		v := s.Default
+		if s.Type == "generated" && v == "" {
+			generated, err := randomValue()
+			if err != nil {
+				return nil, fmt.Errorf("pluginmanifest: generate %q: %w", s.Key, err)
+			}
+			v = generated
+		}
+		if err := store.Set(m.Name, s.Key, v); err != nil {
+			return nil, fmt.Errorf("pluginmanifest: persist %q: %w", s.Key, err)
+		}
+		out[s.Key] = v
+	}
+	return out, nil
+}
+
+func randomValue() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,127 @@
+package pluginmanifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cyrup-ai/kargo/pkg/wasmhost"
+)
+
+func TestManifestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Manifest
+		wantErr bool
+	}{
+		{
+			name: "valid settings",
+			m: Manifest{
+				Name: "example",
+				Settings: []wasmhost.PluginSetting{
+					{Key: "token", Type: "text"},
+					{Key: "mode", Type: "dropdown", Options: []wasmhost.PluginOption{{Value: "a"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "empty key",
+			m:       Manifest{Settings: []wasmhost.PluginSetting{{Key: "", Type: "text"}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate key",
+			m: Manifest{Settings: []wasmhost.PluginSetting{
+				{Key: "token", Type: "text"},
+				{Key: "token", Type: "bool"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			m:       Manifest{Settings: []wasmhost.PluginSetting{{Key: "token", Type: "bogus"}}},
+			wantErr: true,
+		},
+		{
+			name:    "dropdown without options",
+			m:       Manifest{Settings: []wasmhost.PluginSetting{{Key: "mode", Type: "dropdown"}}},
+			wantErr: true,
+		},
+		{
+			name:    "radio without options",
+			m:       Manifest{Settings: []wasmhost.PluginSetting{{Key: "mode", Type: "radio"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestManifestResolve(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "plugin-settings.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	m := &Manifest{
+		Name: "example",
+		Settings: []wasmhost.PluginSetting{
+			{Key: "input", Type: "text", Default: "fallback"},
+			{Key: "secret", Type: "generated"},
+		},
+	}
+
+	resolved, err := m.Resolve(store)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resolved["input"] != "fallback" {
+		t.Errorf("resolved[\"input\"] = %q, want %q", resolved["input"], "fallback")
+	}
+	if resolved["secret"] == "" {
+		t.Error("expected a generated value for \"secret\", got empty string")
+	}
+
+	// A second Resolve against the same store must return the same values,
+	// including the generated one persisted by the first call.
+	resolvedAgain, err := m.Resolve(store)
+	if err != nil {
+		t.Fatalf("second Resolve: %v", err)
+	}
+	if resolvedAgain["secret"] != resolved["secret"] {
+		t.Errorf("generated value changed across resolves: %q != %q", resolvedAgain["secret"], resolved["secret"])
+	}
+}
+
+func TestManifestResolveUsesStoredValueOverDefault(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "plugin-settings.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	if err := store.Set("example", "input", "stored-value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	m := &Manifest{
+		Name:     "example",
+		Settings: []wasmhost.PluginSetting{{Key: "input", Type: "text", Default: "fallback"}},
+	}
+
+	resolved, err := m.Resolve(store)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if resolved["input"] != "stored-value" {
+		t.Errorf("resolved[\"input\"] = %q, want %q", resolved["input"], "stored-value")
+	}
+}
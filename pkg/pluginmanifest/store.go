@@ -0,0 +1,58 @@
+package pluginmanifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// FileStore is a ConfigStore backed by a single JSON file, keyed
+// pluginName -> settingKey -> value. It is the default store used by the
+// kargo plugin CLI.
+type FileStore struct {
+	path string
+	data map[string]map[string]string
+}
+
+// NewFileStore loads (or initializes) a FileStore backed by path.
+func NewFileStore(path string) (*FileStore, error) {
+	fs := &FileStore{path: path, data: make(map[string]map[string]string)}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fs.data); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// Get implements ConfigStore.
+func (fs *FileStore) Get(pluginName, key string) (string, bool) {
+	v, ok := fs.data[pluginName][key]
+	return v, ok
+}
+
+// Set implements ConfigStore and immediately persists the updated store.
+func (fs *FileStore) Set(pluginName, key, value string) error {
+	if fs.data[pluginName] == nil {
+		fs.data[pluginName] = make(map[string]string)
+	}
+	fs.data[pluginName][key] = value
+	return fs.save()
+}
+
+func (fs *FileStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(fs.path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(fs.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fs.path, b, 0o600)
+}
@@ -0,0 +1,175 @@
+package pluginregistry
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Installer resolves, clones, builds, and installs plugin refs.
+type Installer struct {
+	// CacheDir holds shallow clones and the sha256 lockfile, e.g.
+	// $XDG_CACHE_HOME/kargo/plugins.
+	CacheDir string
+	// PluginDir is where built .wasm files are installed, i.e. the
+	// directory a wasmhost.Registry scans.
+	PluginDir string
+}
+
+// NewInstaller creates an Installer using the given cache and plugin
+// directories, creating them if necessary.
+func NewInstaller(cacheDir, pluginDir string) (*Installer, error) {
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("pluginregistry: create cache dir: %w", err)
+	}
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		return nil, fmt.Errorf("pluginregistry: create plugin dir: %w", err)
+	}
+	return &Installer{CacheDir: cacheDir, PluginDir: pluginDir}, nil
+}
+
+// Install resolves ref, shallow-clones it into the cache dir, builds the
+// plugin with TinyGo targeting wasi, installs the resulting .wasm into
+// PluginDir, and records/verifies its checksum against the cache dir's
+// private lockfile. It returns the path to the installed .wasm.
+//
+// This lockfile is per-machine, so it only catches a ref's build drifting
+// underneath repeated installs on this one machine; it does not guarantee
+// the same ref builds identically on a different machine. For that
+// guarantee, install via a project's .kargo.yml and InstallAll, which
+// verifies against a lockfile committed next to it instead.
+func (in *Installer) Install(refStr string) (string, error) {
+	return in.InstallWithVars(refStr, nil)
+}
+
+// InstallWithVars is Install, but first renders every "{{key}}" placeholder
+// in the cloned source tree using vars (see PluginDependency.Vars) before
+// handing it to TinyGo. A nil or empty vars builds the clone as-is.
+func (in *Installer) InstallWithVars(refStr string, vars map[string]string) (string, error) {
+	lf, err := loadLockfile(in.CacheDir)
+	if err != nil {
+		return "", err
+	}
+	return in.install(refStr, vars, lf)
+}
+
+// install does the work shared by Install/InstallWithVars and InstallAll,
+// verifying the built .wasm against lf rather than always loading the
+// cache dir's own lockfile, so InstallAll can verify against a lockfile
+// shared across machines instead.
+func (in *Installer) install(refStr string, vars map[string]string, lf *Lockfile) (string, error) {
+	ref, err := ParseRef(refStr)
+	if err != nil {
+		return "", err
+	}
+
+	repoDir := filepath.Join(in.CacheDir, "repos", ref.Host, ref.Owner, ref.Repo, ref.Rev)
+	if err := cloneShallow(ref, repoDir); err != nil {
+		return "", err
+	}
+
+	srcDir := filepath.Join(repoDir, ref.Subpath)
+	if len(vars) > 0 {
+		renderedDir, err := os.MkdirTemp("", "kargo-plugin-scaffold-*")
+		if err != nil {
+			return "", fmt.Errorf("pluginregistry: create scaffold dir: %w", err)
+		}
+		defer os.RemoveAll(renderedDir)
+		if err := substituteVars(srcDir, renderedDir, vars); err != nil {
+			return "", fmt.Errorf("pluginregistry: render %s: %w", refStr, err)
+		}
+		srcDir = renderedDir
+	}
+
+	outPath := filepath.Join(in.PluginDir, ref.Repo+".wasm")
+	if err := buildWithTinyGo(srcDir, outPath); err != nil {
+		return "", err
+	}
+
+	if err := lf.Verify(ref.String(), outPath); err != nil {
+		return "", err
+	}
+
+	return outPath, nil
+}
+
+// cloneShallow shallow-clones ref into dest. Branch and tag revs are cloned
+// directly with --branch; a commit-SHA rev (ParseRef's @<ref> grammar
+// allows both) is fetched and checked out separately, since
+// "git clone --branch" only resolves refs, not arbitrary commits.
+func cloneShallow(ref Ref, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		// Already cloned at this pinned rev; nothing to refresh.
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("pluginregistry: create %s: %w", filepath.Dir(dest), err)
+	}
+
+	if isCommitSHA(ref.Rev) {
+		return cloneAndCheckoutCommit(ref, dest)
+	}
+
+	cmd := exec.Command("git", "clone", "--depth=1", "--branch", ref.Rev, ref.CloneURL(), dest)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pluginregistry: git clone %s: %w", ref, err)
+	}
+	return nil
+}
+
+// isCommitSHA reports whether rev looks like a (possibly abbreviated) git
+// commit hash rather than a branch or tag name.
+func isCommitSHA(rev string) bool {
+	if len(rev) < 7 || len(rev) > 40 {
+		return false
+	}
+	for _, r := range rev {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// cloneAndCheckoutCommit fetches the single commit ref.Rev by SHA and
+// checks it out into dest. Hosts that support fetching arbitrary reachable
+// commits by hash (GitHub included) allow this even though
+// "git clone --branch" only accepts refs.
+func cloneAndCheckoutCommit(ref Ref, dest string) error {
+	if err := exec.Command("git", "init", dest).Run(); err != nil {
+		return fmt.Errorf("pluginregistry: git init %s: %w", dest, err)
+	}
+
+	run := func(args ...string) error {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dest
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	if err := run("remote", "add", "origin", ref.CloneURL()); err != nil {
+		return fmt.Errorf("pluginregistry: git remote add %s: %w", ref, err)
+	}
+	if err := run("fetch", "--depth=1", "origin", ref.Rev); err != nil {
+		return fmt.Errorf("pluginregistry: git fetch %s: %w", ref, err)
+	}
+	if err := run("checkout", "FETCH_HEAD"); err != nil {
+		return fmt.Errorf("pluginregistry: git checkout %s: %w", ref, err)
+	}
+	return nil
+}
+
+func buildWithTinyGo(srcDir, outPath string) error {
+	cmd := exec.Command("tinygo", "build", "-o", outPath, "-target=wasi", srcDir)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pluginregistry: tinygo build %s: %w", srcDir, err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package pluginregistry
+
+import "testing"
+
+func TestIsCommitSHA(t *testing.T) {
+	tests := []struct {
+		rev  string
+		want bool
+	}{
+		{"main", false},
+		{"v1.2.0", false},
+		{"abc123", false},      // too short to be an abbreviated SHA
+		{"abc1234", true},      // 7 hex chars, the minimum abbreviated SHA
+		{"deadbeefcafe", true}, // all-hex, plausible abbreviated SHA
+		{hexString(40, 'a'), true},
+		{hexString(40, 'g'), false}, // 'g' isn't a hex digit
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rev, func(t *testing.T) {
+			if got := isCommitSHA(tt.rev); got != tt.want {
+				t.Errorf("isCommitSHA(%q) = %v, want %v", tt.rev, got, tt.want)
+			}
+		})
+	}
+}
+
+func hexString(n int, r rune) string {
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = r
+	}
+	return string(b)
+}
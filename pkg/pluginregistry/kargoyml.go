@@ -0,0 +1,66 @@
+package pluginregistry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KargoFile is the parsed contents of a repo-root .kargo.yml, which lists
+// the plugin dependencies a project expects to have installed.
+type KargoFile struct {
+	Plugins []PluginDependency `yaml:"plugins"`
+
+	// dir is the directory LoadKargoFile read this file from; InstallAll
+	// verifies against the lockfile there so repeated installs from the
+	// same project, on any machine, check against the same checksums
+	// rather than each machine trusting whatever it first built.
+	dir string
+}
+
+// PluginDependency is one entry of KargoFile.Plugins: a pinned ref plus any
+// template variables to substitute when the dependency is scaffolded.
+type PluginDependency struct {
+	Ref  string            `yaml:"ref"`
+	Vars map[string]string `yaml:"vars,omitempty"`
+}
+
+// LoadKargoFile reads and parses the .kargo.yml at path.
+func LoadKargoFile(path string) (*KargoFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pluginregistry: read %s: %w", path, err)
+	}
+	var kf KargoFile
+	if err := yaml.Unmarshal(b, &kf); err != nil {
+		return nil, fmt.Errorf("pluginregistry: parse %s: %w", path, err)
+	}
+	kf.dir = filepath.Dir(path)
+	return &kf, nil
+}
+
+// InstallAll installs every dependency declared in kf, verifying each
+// against the lockfile next to kf's .kargo.yml (rather than the Installer's
+// private cache lockfile) so that installing the same project on a
+// different machine checks against the same committed checksums instead of
+// each machine trusting whatever it first built. It stops at the first
+// failure so a partially-installed lockfile doesn't mask a broken
+// dependency list.
+func (in *Installer) InstallAll(kf *KargoFile) ([]string, error) {
+	lf, err := loadLockfile(kf.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make([]string, 0, len(kf.Plugins))
+	for _, dep := range kf.Plugins {
+		path, err := in.install(dep.Ref, dep.Vars, lf)
+		if err != nil {
+			return installed, fmt.Errorf("pluginregistry: install %s: %w", dep.Ref, err)
+		}
+		installed = append(installed, path)
+	}
+	return installed, nil
+}
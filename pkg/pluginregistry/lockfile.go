@@ -0,0 +1,82 @@
+package pluginregistry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// lockfileName is the name of the sha256 lockfile, kept in the cache dir for
+// Install and next to a project's .kargo.yml for InstallAll, so that
+// repeated installs of the same ref are verified reproducible rather than
+// silently trusting whatever the registry serves this time.
+const lockfileName = "kargo-plugins.lock.json"
+
+// Lockfile maps a ref string to the sha256 of the .wasm it last built to.
+type Lockfile struct {
+	path      string
+	Checksums map[string]string `json:"checksums"`
+}
+
+// loadLockfile reads (or initializes) the lockfile at cacheDir/kargo-plugins.lock.json.
+func loadLockfile(cacheDir string) (*Lockfile, error) {
+	path := filepath.Join(cacheDir, lockfileName)
+	lf := &Lockfile{path: path, Checksums: make(map[string]string)}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lf, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pluginregistry: read lockfile: %w", err)
+	}
+	if err := json.Unmarshal(b, lf); err != nil {
+		return nil, fmt.Errorf("pluginregistry: parse lockfile: %w", err)
+	}
+	return lf, nil
+}
+
+func (lf *Lockfile) save() error {
+	b, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lf.path, b, 0o644)
+}
+
+// Verify checks wasmPath's sha256 against the checksum previously recorded
+// for ref, recording it instead if this is the first install of ref.
+func (lf *Lockfile) Verify(ref string, wasmPath string) error {
+	sum, err := sha256File(wasmPath)
+	if err != nil {
+		return err
+	}
+
+	if existing, ok := lf.Checksums[ref]; ok {
+		if existing != sum {
+			return fmt.Errorf("pluginregistry: checksum mismatch for %s: locked %s, built %s", ref, existing, sum)
+		}
+		return nil
+	}
+
+	lf.Checksums[ref] = sum
+	return lf.save()
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("pluginregistry: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("pluginregistry: hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,73 @@
+package pluginregistry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestLockfileVerifyRecordsFirstInstall(t *testing.T) {
+	dir := t.TempDir()
+	lf, err := loadLockfile(dir)
+	if err != nil {
+		t.Fatalf("loadLockfile: %v", err)
+	}
+
+	wasmPath := writeTempFile(t, dir, "plugin.wasm", "fake wasm bytes")
+	if err := lf.Verify("example.com/owner/repo@v1", wasmPath); err != nil {
+		t.Fatalf("Verify on first install: %v", err)
+	}
+
+	reloaded, err := loadLockfile(dir)
+	if err != nil {
+		t.Fatalf("loadLockfile after save: %v", err)
+	}
+	if _, ok := reloaded.Checksums["example.com/owner/repo@v1"]; !ok {
+		t.Error("expected checksum to be persisted to disk")
+	}
+}
+
+func TestLockfileVerifyMatchesOnReinstall(t *testing.T) {
+	dir := t.TempDir()
+	lf, err := loadLockfile(dir)
+	if err != nil {
+		t.Fatalf("loadLockfile: %v", err)
+	}
+
+	wasmPath := writeTempFile(t, dir, "plugin.wasm", "fake wasm bytes")
+	if err := lf.Verify("example.com/owner/repo@v1", wasmPath); err != nil {
+		t.Fatalf("Verify on first install: %v", err)
+	}
+	if err := lf.Verify("example.com/owner/repo@v1", wasmPath); err != nil {
+		t.Fatalf("Verify on matching reinstall: %v", err)
+	}
+}
+
+func TestLockfileVerifyMismatch(t *testing.T) {
+	dir := t.TempDir()
+	lf, err := loadLockfile(dir)
+	if err != nil {
+		t.Fatalf("loadLockfile: %v", err)
+	}
+
+	wasmPath := writeTempFile(t, dir, "plugin.wasm", "original bytes")
+	if err := lf.Verify("example.com/owner/repo@v1", wasmPath); err != nil {
+		t.Fatalf("Verify on first install: %v", err)
+	}
+
+	if err := os.WriteFile(wasmPath, []byte("tampered bytes"), 0o644); err != nil {
+		t.Fatalf("rewrite wasm: %v", err)
+	}
+	if err := lf.Verify("example.com/owner/repo@v1", wasmPath); err == nil {
+		t.Error("expected checksum mismatch error, got nil")
+	}
+}
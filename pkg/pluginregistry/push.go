@@ -0,0 +1,92 @@
+package pluginregistry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Push copies the plugin sources in localDir into ref's subpath within
+// ref's repo and commits+pushes the result to ref.Rev. Unlike Install, Push
+// needs a full (non-shallow) working copy so it has history to commit onto.
+func (in *Installer) Push(refStr, localDir string) error {
+	ref, err := ParseRef(refStr)
+	if err != nil {
+		return err
+	}
+
+	workDir := filepath.Join(in.CacheDir, "push", ref.Host, ref.Owner, ref.Repo)
+	if err := os.RemoveAll(workDir); err != nil {
+		return fmt.Errorf("pluginregistry: clear %s: %w", workDir, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(workDir), 0o755); err != nil {
+		return fmt.Errorf("pluginregistry: create %s: %w", filepath.Dir(workDir), err)
+	}
+
+	if err := run(filepath.Dir(workDir), "git", "clone", "--branch", ref.Rev, ref.CloneURL(), workDir); err != nil {
+		return fmt.Errorf("pluginregistry: git clone %s: %w", ref, err)
+	}
+
+	destDir := filepath.Join(workDir, ref.Subpath)
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return fmt.Errorf("pluginregistry: create %s: %w", destDir, err)
+	}
+	if err := copyTree(localDir, destDir); err != nil {
+		return err
+	}
+
+	if err := run(workDir, "git", "add", "."); err != nil {
+		return err
+	}
+	if err := run(workDir, "git", "commit", "-m", fmt.Sprintf("push plugin to %s", ref.Subpath)); err != nil {
+		return fmt.Errorf("pluginregistry: git commit: %w", err)
+	}
+	if err := run(workDir, "git", "push", "origin", ref.Rev); err != nil {
+		return fmt.Errorf("pluginregistry: git push %s: %w", ref, err)
+	}
+	return nil
+}
+
+func run(dir, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(path, target, info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,68 @@
+// Package pluginregistry resolves git-hosted plugin references of the form
+// <host>/<owner>/<repo>#<subpath>@<ref>, builds the referenced Go plugin
+// template with TinyGo, and installs the resulting .wasm into a local
+// plugin directory. It backs the `kargo plugin install` and
+// `kargo plugin push` commands.
+package pluginregistry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ref is a parsed plugin reference, e.g.
+// "git.front.kjuulh.io/myorg/myrepo#/plugins/foo@v1.2.0".
+type Ref struct {
+	Host    string
+	Owner   string
+	Repo    string
+	Subpath string // path within the repo to the plugin's Go module; "" means repo root
+	Rev     string // branch, tag, or commit; defaults to "main"
+}
+
+// String reconstructs the canonical reference string for Ref.
+func (r Ref) String() string {
+	s := fmt.Sprintf("%s/%s/%s", r.Host, r.Owner, r.Repo)
+	if r.Subpath != "" {
+		s += "#" + r.Subpath
+	}
+	if r.Rev != "" {
+		s += "@" + r.Rev
+	}
+	return s
+}
+
+// CloneURL returns the https git remote for r.
+func (r Ref) CloneURL() string {
+	return fmt.Sprintf("https://%s/%s/%s.git", r.Host, r.Owner, r.Repo)
+}
+
+// ParseRef parses a reference of the form <host>/<owner>/<repo>#<subpath>@<ref>.
+// Both #<subpath> and @<ref> are optional; Rev defaults to "main".
+func ParseRef(s string) (Ref, error) {
+	rest := s
+	rev := "main"
+	if i := strings.LastIndex(rest, "@"); i != -1 {
+		rev = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	subpath := ""
+	if i := strings.Index(rest, "#"); i != -1 {
+		subpath = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) != 3 {
+		return Ref{}, fmt.Errorf("pluginregistry: %q is not <host>/<owner>/<repo>[#subpath][@ref]", s)
+	}
+
+	return Ref{
+		Host:    parts[0],
+		Owner:   parts[1],
+		Repo:    parts[2],
+		Subpath: strings.Trim(subpath, "/"),
+		Rev:     rev,
+	}, nil
+}
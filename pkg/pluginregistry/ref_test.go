@@ -0,0 +1,61 @@
+package pluginregistry
+
+import "testing"
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want Ref
+	}{
+		{
+			name: "host/owner/repo only defaults rev to main",
+			in:   "git.front.kjuulh.io/myorg/myrepo",
+			want: Ref{Host: "git.front.kjuulh.io", Owner: "myorg", Repo: "myrepo", Subpath: "", Rev: "main"},
+		},
+		{
+			name: "subpath and rev both given",
+			in:   "git.front.kjuulh.io/myorg/myrepo#/plugins/foo@v1.2.0",
+			want: Ref{Host: "git.front.kjuulh.io", Owner: "myorg", Repo: "myrepo", Subpath: "plugins/foo", Rev: "v1.2.0"},
+		},
+		{
+			name: "rev only, no subpath",
+			in:   "github.com/cyrup-ai/kargo@abc123",
+			want: Ref{Host: "github.com", Owner: "cyrup-ai", Repo: "kargo", Subpath: "", Rev: "abc123"},
+		},
+		{
+			name: "subpath only, no rev",
+			in:   "github.com/cyrup-ai/kargo#plugins/foo",
+			want: Ref{Host: "github.com", Owner: "cyrup-ai", Repo: "kargo", Subpath: "plugins/foo", Rev: "main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRef(tt.in)
+			if err != nil {
+				t.Fatalf("ParseRef(%q) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRef(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRefMalformed(t *testing.T) {
+	tests := []string{
+		"",
+		"justahost",
+		"host/owner",
+		"#subpath@rev",
+	}
+
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseRef(in); err == nil {
+				t.Errorf("ParseRef(%q) expected an error, got nil", in)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package pluginregistry
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// substituteVars copies srcDir to destDir, replacing every "{{key}}"
+// placeholder in file contents with vars[key] (e.g. "{{plugin_name}}", as
+// used by plugins/templates/kargo-plugin-template-go). destDir is created if
+// it doesn't already exist.
+func substituteVars(srcDir, destDir string, vars map[string]string) error {
+	var oldnew []string
+	for k, v := range vars {
+		oldnew = append(oldnew, "{{"+k+"}}", v)
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destDir, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("pluginregistry: read %s: %w", path, err)
+		}
+		if err := os.WriteFile(target, []byte(replacer.Replace(string(data))), 0o644); err != nil {
+			return fmt.Errorf("pluginregistry: write %s: %w", target, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,67 @@
+// Package wasmhost loads kargo plugins compiled to WASM and dispatches
+// commands to them through the plugin ABI defined by
+// plugins/templates/kargo-plugin-template-go.
+package wasmhost
+
+// CommandDefinition mirrors the JSON returned by a plugin's get_command
+// export and describes the cobra subcommand the host should register.
+type CommandDefinition struct {
+	Name     string          `json:"name"`
+	About    string          `json:"about"`
+	Args     []ArgDefinition `json:"args"`
+	Settings []PluginSetting `json:"settings,omitempty"`
+}
+
+// ArgDefinition mirrors a single argument of CommandDefinition. Type and
+// Default are enforced host-side by pkg/pluginargs before a plugin is ever
+// invoked; see ArgDefinition.Type constants there (string, int, float,
+// bool, stringSlice, path, duration).
+type ArgDefinition struct {
+	Name       string   `json:"name"`
+	Short      *string  `json:"short,omitempty"`
+	Long       *string  `json:"long,omitempty"`
+	Help       string   `json:"help"`
+	Required   bool     `json:"required"`
+	TakesValue bool     `json:"takesValue"`
+	Type       string   `json:"type,omitempty"`
+	Default    string   `json:"default,omitempty"`
+	Choices    []string `json:"choices,omitempty"`
+}
+
+// PluginMetadata mirrors the JSON returned by a plugin's get_metadata export.
+type PluginMetadata struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Language    string          `json:"language"`
+	Settings    []PluginSetting `json:"settings,omitempty"`
+}
+
+// PluginSetting mirrors a single entry of PluginMetadata.Settings. See
+// pkg/pluginmanifest for the plugin.yaml schema that declares these and the
+// validation/resolution logic that turns them into execute-ready values.
+type PluginSetting struct {
+	Key                string         `json:"key"`
+	Type               string         `json:"type"`
+	DisplayName        string         `json:"displayName"`
+	HelpText           string         `json:"helpText,omitempty"`
+	Default            string         `json:"default,omitempty"`
+	Options            []PluginOption `json:"options,omitempty"`
+	RegenerateHelpText string         `json:"regenerateHelpText,omitempty"`
+}
+
+// PluginOption is one choice in a dropdown or radio PluginSetting.
+type PluginOption struct {
+	Value       string `json:"value"`
+	DisplayName string `json:"displayName"`
+}
+
+// ExecutionResult mirrors the payload of a plugin's terminal "result" event,
+// or, for plugins built against the pre-streaming ABI, the JSON returned by
+// the execute export.
+type ExecutionResult struct {
+	Success bool    `json:"success"`
+	Output  *string `json:"output,omitempty"`
+	Error   *string `json:"error,omitempty"`
+}
@@ -0,0 +1,82 @@
+package wasmhost
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+)
+
+// Fixed offsets the fixture module's data segments live at. 4096 is left as
+// scratch space for malloc, which the fixture always hands out at that one
+// address regardless of requested size.
+const (
+	fixtureCommandOffset  = 0
+	fixtureMetadataOffset = 1024
+	fixtureResultOffset   = 2048
+	fixtureMallocOffset   = 4096
+)
+
+// writeFixturePlugin compiles a minimal WAT module that implements just
+// enough of the plugin ABI for Plugin.Load/GetCommand/GetMetadata/
+// ExecuteStream to exercise against it, and writes the resulting .wasm to
+// path. execute always returns resultJSON via the pre-streaming ABI fallback
+// (a direct ExecutionResult return, no kargo_emit calls) rather than
+// streaming events, since hand-authoring a WAT module that copies dynamic
+// argument bytes into an emitted event isn't worth it for these tests.
+func writeFixturePlugin(t *testing.T, path, commandJSON, metadataJSON, resultJSON string) {
+	t.Helper()
+
+	wat := fmt.Sprintf(`(module
+  (import "kargo" "kargo_emit" (func $kargo_emit (param i32 i32)))
+  (import "kargo" "kargo_should_cancel" (func $kargo_should_cancel (result i32)))
+  (memory (export "memory") 2)
+  (global $last_len (mut i32) (i32.const 0))
+  (data (i32.const %d) %s)
+  (data (i32.const %d) %s)
+  (data (i32.const %d) %s)
+  (func (export "get_command") (result i32)
+    i32.const %d
+    global.set $last_len
+    i32.const %d)
+  (func (export "get_metadata") (result i32)
+    i32.const %d
+    global.set $last_len
+    i32.const %d)
+  (func (export "get_last_result_len") (result i32)
+    global.get $last_len)
+  (func (export "execute") (param i32 i32) (result i32)
+    i32.const %d
+    global.set $last_len
+    i32.const %d)
+  (func (export "malloc") (param i32) (result i32)
+    i32.const %d)
+  (func (export "free") (param i32))
+)`,
+		fixtureCommandOffset, watString(commandJSON),
+		fixtureMetadataOffset, watString(metadataJSON),
+		fixtureResultOffset, watString(resultJSON),
+		len(commandJSON), fixtureCommandOffset,
+		len(metadataJSON), fixtureMetadataOffset,
+		len(resultJSON), fixtureResultOffset,
+		fixtureMallocOffset,
+	)
+
+	wasm, err := wasmtime.Wat2Wasm(wat)
+	if err != nil {
+		t.Fatalf("compile fixture WAT: %v\n%s", err, wat)
+	}
+	if err := os.WriteFile(path, wasm, 0o644); err != nil {
+		t.Fatalf("write fixture %s: %v", path, err)
+	}
+}
+
+// watString renders s as a WAT string literal, escaping backslashes and
+// quotes so arbitrary JSON can be embedded in a data segment.
+func watString(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	return `"` + s + `"`
+}
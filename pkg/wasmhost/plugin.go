@@ -0,0 +1,290 @@
+package wasmhost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+)
+
+// Plugin is a single loaded WASM module along with the store and memory it
+// runs in. Plugins are not safe for concurrent use; callers that need
+// concurrent access should serialize calls with their own lock (see
+// Registry.Reload, which does this across reloads, and Entry.Execute, which
+// does this across concurrent invocations).
+type Plugin struct {
+	Path   string
+	store  *wasmtime.Store
+	inst   *wasmtime.Instance
+	memory *wasmtime.Memory
+
+	getCommand       *wasmtime.Func
+	execute          *wasmtime.Func
+	getMetadata      *wasmtime.Func
+	getLastResultLen *wasmtime.Func
+	malloc           *wasmtime.Func
+	free             *wasmtime.Func
+
+	// onEvent receives each StreamEvent emitted by the guest's kargo_emit
+	// calls during the in-flight ExecuteStream; nil outside of a call.
+	onEvent func(StreamEvent)
+	// cancelled is polled by the guest via the kargo_should_cancel import so
+	// a long-running plugin can stop early once ExecuteStream's context is
+	// done.
+	cancelled atomic.Bool
+}
+
+// Load compiles and instantiates the WASM module at path under WASI and
+// resolves the exports required by the plugin ABI.
+func Load(engine *wasmtime.Engine, path string) (*Plugin, error) {
+	module, err := wasmtime.NewModuleFromFile(engine, path)
+	if err != nil {
+		return nil, fmt.Errorf("wasmhost: compile %s: %w", path, err)
+	}
+
+	store := wasmtime.NewStore(engine)
+	wasiConfig := wasmtime.NewWasiConfig()
+	wasiConfig.InheritStdout()
+	wasiConfig.InheritStderr()
+	store.SetWasi(wasiConfig)
+
+	// p is referenced by the kargo_emit/kargo_should_cancel closures below
+	// before it's fully populated; both only read fields set later (memory,
+	// onEvent, cancelled), which is safe since neither import is called
+	// until ExecuteStream runs, long after Load returns.
+	p := &Plugin{Path: path, store: store}
+
+	linker := wasmtime.NewLinker(engine)
+	if err := linker.DefineWasi(); err != nil {
+		return nil, fmt.Errorf("wasmhost: define wasi for %s: %w", path, err)
+	}
+	if err := linker.FuncWrap("kargo", "kargo_emit", func(ptr int32, length int32) {
+		p.handleEmit(ptr, length)
+	}); err != nil {
+		return nil, fmt.Errorf("wasmhost: define kargo_emit for %s: %w", path, err)
+	}
+	if err := linker.FuncWrap("kargo", "kargo_should_cancel", func() int32 {
+		if p.cancelled.Load() {
+			return 1
+		}
+		return 0
+	}); err != nil {
+		return nil, fmt.Errorf("wasmhost: define kargo_should_cancel for %s: %w", path, err)
+	}
+
+	inst, err := linker.Instantiate(store, module)
+	if err != nil {
+		return nil, fmt.Errorf("wasmhost: instantiate %s: %w", path, err)
+	}
+	p.inst = inst
+
+	mem := inst.GetExport(store, "memory")
+	if mem == nil || mem.Memory() == nil {
+		return nil, fmt.Errorf("wasmhost: %s does not export linear memory", path)
+	}
+	p.memory = mem.Memory()
+
+	for name, slot := range map[string]**wasmtime.Func{
+		"get_command":         &p.getCommand,
+		"execute":             &p.execute,
+		"get_metadata":        &p.getMetadata,
+		"get_last_result_len": &p.getLastResultLen,
+	} {
+		exp := inst.GetExport(store, name)
+		if exp == nil || exp.Func() == nil {
+			return nil, fmt.Errorf("wasmhost: %s does not export %s", path, name)
+		}
+		*slot = exp.Func()
+	}
+
+	// malloc/free are optional: they're only needed to pass argument bytes
+	// into the guest. The current template exports both explicitly (see
+	// plugins/templates/kargo-plugin-template-go); plugins built without
+	// them can only be invoked with empty args.
+	if exp := inst.GetExport(store, "malloc"); exp != nil {
+		p.malloc = exp.Func()
+	}
+	if exp := inst.GetExport(store, "free"); exp != nil {
+		p.free = exp.Func()
+	}
+
+	return p, nil
+}
+
+// readResult copies the bytes most recently produced by a ptr-returning
+// export out of the plugin's linear memory, using get_last_result_len to
+// learn how many bytes to copy.
+func (p *Plugin) readResult(ptr int32) ([]byte, error) {
+	n, err := p.getLastResultLen.Call(p.store)
+	if err != nil {
+		return nil, fmt.Errorf("wasmhost: get_last_result_len: %w", err)
+	}
+	length := n.(int32)
+	if length == 0 {
+		return nil, nil
+	}
+	data := p.memory.UnsafeData(p.store)
+	if int(ptr)+int(length) > len(data) {
+		return nil, fmt.Errorf("wasmhost: result [%d:%d] out of bounds (memory size %d)", ptr, int(ptr)+int(length), len(data))
+	}
+	out := make([]byte, length)
+	copy(out, data[ptr:int(ptr)+int(length)])
+	return out, nil
+}
+
+// writeArgs copies b into the plugin's linear memory via its malloc export
+// and returns the pointer it was written at.
+func (p *Plugin) writeArgs(b []byte) (int32, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	if p.malloc == nil {
+		return 0, fmt.Errorf("wasmhost: %s does not export malloc, cannot pass arguments", p.Path)
+	}
+	ret, err := p.malloc.Call(p.store, int32(len(b)))
+	if err != nil {
+		return 0, fmt.Errorf("wasmhost: malloc: %w", err)
+	}
+	ptr := ret.(int32)
+	mem := p.memory.UnsafeData(p.store)
+	copy(mem[ptr:int(ptr)+len(b)], b)
+	return ptr, nil
+}
+
+// freeArgs releases a buffer previously returned by writeArgs, if the plugin
+// exports free and the pointer is non-zero (writeArgs returns 0 for empty
+// args without calling malloc, so there's nothing to release in that case).
+func (p *Plugin) freeArgs(ptr int32) {
+	if p.free == nil || ptr == 0 {
+		return
+	}
+	_, _ = p.free.Call(p.store, ptr)
+}
+
+// GetCommand calls the plugin's get_command export and decodes the result.
+func (p *Plugin) GetCommand() (*CommandDefinition, error) {
+	ret, err := p.getCommand.Call(p.store)
+	if err != nil {
+		return nil, fmt.Errorf("wasmhost: get_command: %w", err)
+	}
+	raw, err := p.readResult(ret.(int32))
+	if err != nil {
+		return nil, err
+	}
+	var cmd CommandDefinition
+	if err := json.Unmarshal(raw, &cmd); err != nil {
+		return nil, fmt.Errorf("wasmhost: decode CommandDefinition: %w", err)
+	}
+	return &cmd, nil
+}
+
+// GetMetadata calls the plugin's get_metadata export and decodes the result.
+func (p *Plugin) GetMetadata() (*PluginMetadata, error) {
+	ret, err := p.getMetadata.Call(p.store)
+	if err != nil {
+		return nil, fmt.Errorf("wasmhost: get_metadata: %w", err)
+	}
+	raw, err := p.readResult(ret.(int32))
+	if err != nil {
+		return nil, err
+	}
+	var md PluginMetadata
+	if err := json.Unmarshal(raw, &md); err != nil {
+		return nil, fmt.Errorf("wasmhost: decode PluginMetadata: %w", err)
+	}
+	return &md, nil
+}
+
+// handleEmit decodes a StreamEvent out of linear memory and forwards it to
+// the onEvent callback of the in-flight ExecuteStream. It is registered as
+// the kargo_emit import and called directly by the guest, potentially many
+// times per execute call.
+func (p *Plugin) handleEmit(ptr, length int32) {
+	if p.onEvent == nil || length == 0 {
+		return
+	}
+	data := p.memory.UnsafeData(p.store)
+	if int(ptr)+int(length) > len(data) {
+		return
+	}
+	raw := make([]byte, length)
+	copy(raw, data[ptr:int(ptr)+int(length)])
+
+	var ev StreamEvent
+	if err := json.Unmarshal(raw, &ev); err != nil {
+		return
+	}
+	p.onEvent(ev)
+}
+
+// ExecuteStream marshals args to JSON and calls the plugin's execute
+// export, invoking onEvent for every event the guest emits via kargo_emit.
+// It returns the terminal "result" event's payload. If ctx is canceled or
+// its deadline passes before execute returns, the kargo_should_cancel flag
+// a well-behaved guest polls is set, but ExecuteStream still waits for
+// execute to actually return.
+//
+// Plugins built against the pre-streaming ABI (chunk0-1) that return their
+// ExecutionResult directly from execute rather than emitting it are still
+// supported as a fallback.
+func (p *Plugin) ExecuteStream(ctx context.Context, args map[string]interface{}, onEvent func(StreamEvent)) (*ExecutionResult, error) {
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("wasmhost: marshal args: %w", err)
+	}
+	ptr, err := p.writeArgs(argsJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer p.freeArgs(ptr)
+
+	var result *ExecutionResult
+	p.onEvent = func(ev StreamEvent) {
+		if result != nil {
+			// A terminal "result" event has already closed the stream.
+			return
+		}
+		if onEvent != nil {
+			onEvent(ev)
+		}
+		if ev.IsTerminal() {
+			r := ev.toExecutionResult()
+			result = &r
+		}
+	}
+	defer func() { p.onEvent = nil }()
+
+	p.cancelled.Store(false)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.cancelled.Store(true)
+		case <-done:
+		}
+	}()
+
+	ret, err := p.execute.Call(p.store, ptr, int32(len(argsJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("wasmhost: execute: %w", err)
+	}
+
+	if result == nil && ret.(int32) != 0 {
+		raw, err := p.readResult(ret.(int32))
+		if err != nil {
+			return nil, err
+		}
+		var r ExecutionResult
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("wasmhost: decode ExecutionResult: %w", err)
+		}
+		result = &r
+	}
+	if result == nil {
+		return nil, fmt.Errorf("wasmhost: %s: execute ended without a terminal result event", p.Path)
+	}
+	return result, nil
+}
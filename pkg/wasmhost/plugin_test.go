@@ -0,0 +1,89 @@
+package wasmhost
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+)
+
+func TestPluginLoadGetCommandGetMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.wasm")
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"says hello","args":[]}`,
+		`{"name":"greeter","version":"1.0.0","description":"a test plugin","author":"kargo","language":"go"}`,
+		`{"success":true,"output":"hi"}`,
+	)
+
+	p, err := Load(wasmtime.NewEngine(), path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	cmd, err := p.GetCommand()
+	if err != nil {
+		t.Fatalf("GetCommand: %v", err)
+	}
+	if cmd.Name != "greet" || cmd.About != "says hello" {
+		t.Errorf("GetCommand = %+v, want Name=greet About=%q", cmd, "says hello")
+	}
+
+	md, err := p.GetMetadata()
+	if err != nil {
+		t.Fatalf("GetMetadata: %v", err)
+	}
+	if md.Name != "greeter" || md.Version != "1.0.0" {
+		t.Errorf("GetMetadata = %+v, want Name=greeter Version=1.0.0", md)
+	}
+}
+
+func TestPluginExecuteStreamReturnsTerminalResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.wasm")
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"says hello","args":[]}`,
+		`{"name":"greeter","version":"1.0.0","description":"a test plugin","author":"kargo","language":"go"}`,
+		`{"success":true,"output":"hi"}`,
+	)
+
+	p, err := Load(wasmtime.NewEngine(), path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	result, err := p.ExecuteStream(context.Background(), map[string]interface{}{"name": "world"}, nil)
+	if err != nil {
+		t.Fatalf("ExecuteStream: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("result.Success = false, want true")
+	}
+	if result.Output == nil || *result.Output != "hi" {
+		t.Errorf("result.Output = %v, want %q", result.Output, "hi")
+	}
+}
+
+func TestPluginExecuteStreamReportsFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.wasm")
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"says hello","args":[]}`,
+		`{"name":"greeter","version":"1.0.0","description":"a test plugin","author":"kargo","language":"go"}`,
+		`{"success":false,"error":"boom"}`,
+	)
+
+	p, err := Load(wasmtime.NewEngine(), path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	result, err := p.ExecuteStream(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("ExecuteStream: %v", err)
+	}
+	if result.Success {
+		t.Errorf("result.Success = true, want false")
+	}
+	if result.Error == nil || *result.Error != "boom" {
+		t.Errorf("result.Error = %v, want %q", result.Error, "boom")
+	}
+}
@@ -0,0 +1,222 @@
+package wasmhost
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+)
+
+// Entry is a loaded plugin together with the metadata and command
+// definition it reported at load time.
+type Entry struct {
+	Plugin          *Plugin
+	Metadata        *PluginMetadata
+	Command         *CommandDefinition
+	Enabled         bool
+	LastReloadError error
+
+	mu sync.RWMutex
+	// execMu serializes Execute calls against the current Plugin, which is
+	// not itself safe for concurrent use (see Plugin's doc comment). It is
+	// held for the full duration of a call, independently of mu, which only
+	// guards the Plugin field itself against a concurrent Reload.
+	execMu sync.Mutex
+}
+
+// Execute dispatches to the entry's current Plugin, holding a read lock so
+// that a concurrent Registry.Reload drains in-flight calls like this one
+// against the old instance before swapping it out, and an exec lock so that
+// concurrent Execute calls against the same Plugin are serialized rather
+// than racing on its shared store and event state. onEvent is called for
+// every event the plugin emits over the streaming ABI; see
+// Plugin.ExecuteStream.
+func (e *Entry) Execute(ctx context.Context, args map[string]interface{}, onEvent func(StreamEvent)) (*ExecutionResult, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	e.execMu.Lock()
+	defer e.execMu.Unlock()
+	return e.Plugin.ExecuteStream(ctx, args, onEvent)
+}
+
+// Registry tracks every plugin discovered in a plugin directory.
+type Registry struct {
+	Dir string
+
+	mu      sync.RWMutex
+	engine  *wasmtime.Engine
+	entries map[string]*Entry
+}
+
+// NewRegistry creates a Registry rooted at dir. dir is not scanned until
+// Discover is called.
+func NewRegistry(dir string) *Registry {
+	return &Registry{
+		Dir:     dir,
+		engine:  wasmtime.NewEngine(),
+		entries: make(map[string]*Entry),
+	}
+}
+
+// Discover scans Dir for *.wasm files, loads each one, and registers it
+// under the name reported by its get_metadata export. A plugin that fails
+// to load is skipped with its error returned alongside the others, so that
+// one broken plugin doesn't prevent the rest from loading.
+func (r *Registry) Discover() []error {
+	matches, err := filepath.Glob(filepath.Join(r.Dir, "*.wasm"))
+	if err != nil {
+		return []error{fmt.Errorf("wasmhost: glob %s: %w", r.Dir, err)}
+	}
+
+	var errs []error
+	for _, path := range matches {
+		if err := r.load(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (r *Registry) load(path string) error {
+	p, err := Load(r.engine, path)
+	if err != nil {
+		return err
+	}
+	md, err := p.GetMetadata()
+	if err != nil {
+		return fmt.Errorf("wasmhost: %s: %w", path, err)
+	}
+	cmd, err := p.GetCommand()
+	if err != nil {
+		return fmt.Errorf("wasmhost: %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[md.Name] = &Entry{Plugin: p, Metadata: md, Command: cmd, Enabled: true}
+	return nil
+}
+
+// Get returns the entry registered under name, if any.
+func (r *Registry) Get(name string) (*Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.entries[name]
+	return e, ok
+}
+
+// List returns every registered entry, sorted by name.
+func (r *Registry) List() []*Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]*Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Metadata.Name < out[j].Metadata.Name })
+	return out
+}
+
+// SetEnabled toggles whether name's entry should be registered as a cobra
+// subcommand; it does not unload the plugin.
+func (r *Registry) SetEnabled(name string, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[name]
+	if !ok {
+		return fmt.Errorf("wasmhost: no such plugin %q", name)
+	}
+	e.Enabled = enabled
+	return nil
+}
+
+// Remove deletes name's entry from the registry and removes its .wasm file
+// from Dir.
+func (r *Registry) Remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[name]
+	if !ok {
+		return fmt.Errorf("wasmhost: no such plugin %q", name)
+	}
+	delete(r.entries, name)
+	return os.Remove(e.Plugin.Path)
+}
+
+// NameForPath returns the registered name of the plugin loaded from path, if
+// any.
+func (r *Registry) NameForPath(path string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, e := range r.entries {
+		if e.Plugin.Path == path {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Reload recompiles the plugin named name from its original .wasm path and,
+// only if the new module instantiates and reports the same name via
+// get_metadata, swaps it in atomically under the entry's lock. In-flight
+// Entry.Execute calls against the old instance are allowed to drain first.
+// If the new module fails to load or is incompatible, the previous instance
+// keeps serving and the failure is recorded on Entry.LastReloadError.
+func (r *Registry) Reload(name string) error {
+	r.mu.RLock()
+	e, ok := r.entries[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("wasmhost: no such plugin %q", name)
+	}
+
+	newPlugin, err := Load(r.engine, e.Plugin.Path)
+	if err == nil {
+		var md *PluginMetadata
+		md, err = newPlugin.GetMetadata()
+		if err == nil && md.Name != name {
+			err = fmt.Errorf("plugin renamed itself from %q to %q on reload", name, md.Name)
+		}
+		if err == nil {
+			var cmd *CommandDefinition
+			cmd, err = newPlugin.GetCommand()
+			if err == nil {
+				e.mu.Lock()
+				e.Plugin = newPlugin
+				e.Metadata = md
+				e.Command = cmd
+				e.LastReloadError = nil
+				e.mu.Unlock()
+				return nil
+			}
+		}
+	}
+
+	e.mu.Lock()
+	e.LastReloadError = err
+	e.mu.Unlock()
+	return fmt.Errorf("wasmhost: reload %s: %w", name, err)
+}
+
+// ReloadAll reloads every registered plugin, collecting rather than
+// stopping at individual failures, the same way Discover does.
+func (r *Registry) ReloadAll() []error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, name := range names {
+		if err := r.Reload(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,117 @@
+package wasmhost
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistryDiscoverGetList(t *testing.T) {
+	dir := t.TempDir()
+	writeFixturePlugin(t, filepath.Join(dir, "bravo.wasm"),
+		`{"name":"bravo-cmd","about":"b","args":[]}`,
+		`{"name":"bravo","version":"1.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true}`,
+	)
+	writeFixturePlugin(t, filepath.Join(dir, "alpha.wasm"),
+		`{"name":"alpha-cmd","about":"a","args":[]}`,
+		`{"name":"alpha","version":"1.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true}`,
+	)
+
+	reg := NewRegistry(dir)
+	if errs := reg.Discover(); len(errs) > 0 {
+		t.Fatalf("Discover returned errors: %v", errs)
+	}
+
+	if _, ok := reg.Get("alpha"); !ok {
+		t.Error("Get(\"alpha\") not found")
+	}
+	if _, ok := reg.Get("missing"); ok {
+		t.Error("Get(\"missing\") unexpectedly found")
+	}
+
+	list := reg.List()
+	if len(list) != 2 {
+		t.Fatalf("List returned %d entries, want 2", len(list))
+	}
+	if list[0].Metadata.Name != "alpha" || list[1].Metadata.Name != "bravo" {
+		t.Errorf("List order = [%s, %s], want [alpha, bravo]", list[0].Metadata.Name, list[1].Metadata.Name)
+	}
+}
+
+func TestRegistryReloadPicksUpChangedFixture(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greeter.wasm")
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"v1","args":[]}`,
+		`{"name":"greeter","version":"1.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true,"output":"v1"}`,
+	)
+
+	reg := NewRegistry(dir)
+	if errs := reg.Discover(); len(errs) > 0 {
+		t.Fatalf("Discover returned errors: %v", errs)
+	}
+
+	e, ok := reg.Get("greeter")
+	if !ok {
+		t.Fatal("Get(\"greeter\") not found after Discover")
+	}
+	if e.Command.About != "v1" {
+		t.Fatalf("Command.About = %q, want v1", e.Command.About)
+	}
+
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"v2","args":[]}`,
+		`{"name":"greeter","version":"2.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true,"output":"v2"}`,
+	)
+
+	if err := reg.Reload("greeter"); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	e, ok = reg.Get("greeter")
+	if !ok {
+		t.Fatal("Get(\"greeter\") not found after Reload")
+	}
+	if e.Command.About != "v2" || e.Metadata.Version != "2.0.0" {
+		t.Errorf("after Reload: Command.About=%q Metadata.Version=%q, want v2/2.0.0", e.Command.About, e.Metadata.Version)
+	}
+	if e.LastReloadError != nil {
+		t.Errorf("LastReloadError = %v, want nil", e.LastReloadError)
+	}
+}
+
+func TestRegistryReloadRejectsRename(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greeter.wasm")
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"v1","args":[]}`,
+		`{"name":"greeter","version":"1.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true}`,
+	)
+
+	reg := NewRegistry(dir)
+	if errs := reg.Discover(); len(errs) > 0 {
+		t.Fatalf("Discover returned errors: %v", errs)
+	}
+
+	writeFixturePlugin(t, path,
+		`{"name":"greet","about":"v1","args":[]}`,
+		`{"name":"renamed","version":"1.0.0","description":"","author":"","language":"go"}`,
+		`{"success":true}`,
+	)
+
+	if err := reg.Reload("greeter"); err == nil {
+		t.Fatal("Reload expected an error when the plugin renames itself, got nil")
+	}
+
+	e, ok := reg.Get("greeter")
+	if !ok {
+		t.Fatal("Get(\"greeter\") should still resolve to the previous instance after a rejected reload")
+	}
+	if e.LastReloadError == nil {
+		t.Error("LastReloadError = nil, want the rename error recorded")
+	}
+}
@@ -0,0 +1,62 @@
+package wasmhost
+
+import "encoding/json"
+
+// StreamEvent is one event emitted by a plugin's execute export over the
+// kargo_emit streaming ABI. Kind is "log", "progress", or "result"; a
+// "result" event is terminal and ends the stream.
+type StreamEvent struct {
+	Kind  string `json:"kind"`
+	Level string `json:"level,omitempty"`
+	Msg   string `json:"msg,omitempty"`
+	Pct   int    `json:"pct,omitempty"`
+
+	Success bool    `json:"success"`
+	Output  *string `json:"output,omitempty"`
+	Error   *string `json:"error,omitempty"`
+}
+
+// IsTerminal reports whether this event ends the stream.
+func (e StreamEvent) IsTerminal() bool {
+	return e.Kind == "result"
+}
+
+// logEvent and progressEvent are the wire shapes for "log" and "progress"
+// events: they carry no success/output/error fields, so marshaling them
+// directly would leak a spurious "success":false onto every non-terminal
+// event. resultEvent is the wire shape for the terminal "result" event.
+type logEvent struct {
+	Kind  string `json:"kind"`
+	Level string `json:"level,omitempty"`
+	Msg   string `json:"msg,omitempty"`
+}
+
+type progressEvent struct {
+	Kind string `json:"kind"`
+	Pct  int    `json:"pct"`
+}
+
+type resultEvent struct {
+	Kind    string  `json:"kind"`
+	Success bool    `json:"success"`
+	Output  *string `json:"output,omitempty"`
+	Error   *string `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes e as its Kind-specific wire shape so that "log" and
+// "progress" events never carry the "success"/"output"/"error" fields that
+// only apply to the terminal "result" event.
+func (e StreamEvent) MarshalJSON() ([]byte, error) {
+	switch e.Kind {
+	case "progress":
+		return json.Marshal(progressEvent{Kind: e.Kind, Pct: e.Pct})
+	case "result":
+		return json.Marshal(resultEvent{Kind: e.Kind, Success: e.Success, Output: e.Output, Error: e.Error})
+	default:
+		return json.Marshal(logEvent{Kind: e.Kind, Level: e.Level, Msg: e.Msg})
+	}
+}
+
+func (e StreamEvent) toExecutionResult() ExecutionResult {
+	return ExecutionResult{Success: e.Success, Output: e.Output, Error: e.Error}
+}
@@ -7,32 +7,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"unsafe"
+
+	"github.com/cyrup-ai/kargo/pkg/pluginargs"
 )
 
 // CommandDefinition represents the CLI command structure
 type CommandDefinition struct {
-	Name  string          `json:"name"`
-	About string          `json:"about"`
-	Args  []ArgDefinition `json:"args"`
-}
-
-// ArgDefinition represents a command argument
-type ArgDefinition struct {
-	Name       string  `json:"name"`
-	Short      *string `json:"short,omitempty"`
-	Long       *string `json:"long,omitempty"`
-	Help       string  `json:"help"`
-	Required   bool    `json:"required"`
-	TakesValue bool    `json:"takesValue"`
+	Name     string                     `json:"name"`
+	About    string                     `json:"about"`
+	Args     []pluginargs.ArgDefinition `json:"args"`
+	Settings []PluginSetting            `json:"settings,omitempty"`
 }
 
 // PluginMetadata contains plugin information
 type PluginMetadata struct {
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	Description string `json:"description"`
-	Author      string `json:"author"`
-	Language    string `json:"language"`
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Author      string          `json:"author"`
+	Language    string          `json:"language"`
+	Settings    []PluginSetting `json:"settings,omitempty"`
+}
+
+// PluginSetting declares a single piece of user-configurable state the host
+// should collect, validate, and persist for this plugin, then inject into
+// the args JSON passed to execute under the same Key.
+type PluginSetting struct {
+	Key                string         `json:"key"`
+	Type               string         `json:"type"` // bool, text, dropdown, radio, generated, number, username
+	DisplayName        string         `json:"displayName"`
+	HelpText           string         `json:"helpText,omitempty"`
+	Default            string         `json:"default,omitempty"`
+	Options            []PluginOption `json:"options,omitempty"`
+	RegenerateHelpText string         `json:"regenerateHelpText,omitempty"`
+}
+
+// PluginOption is one choice in a dropdown or radio PluginSetting.
+type PluginOption struct {
+	Value       string `json:"value"`
+	DisplayName string `json:"displayName"`
 }
 
 // ExecutionResult represents the plugin execution result
@@ -42,43 +55,135 @@ type ExecutionResult struct {
 	Error   *string `json:"error,omitempty"`
 }
 
+// lastResult holds the most recently encoded return value so the host can
+// read it back with get_last_result_len before dereferencing the pointer.
+// WASM exports can only return a single i32, so a (ptr, len) tuple has to be
+// split across two host calls rather than returned in one.
+var lastResult []byte
+
+// setResult stashes data as the pending result and returns a pointer to its
+// first byte (or nil for an empty/failed encode), for use as an export's
+// return value.
+func setResult(data []byte) *byte {
+	lastResult = data
+	if len(data) == 0 {
+		return nil
+	}
+	return &data[0]
+}
+
+// GetLastResultLen returns the length in bytes of the value most recently
+// returned by get_command or get_metadata. The host must call this
+// immediately after each of those exports to know how many bytes to copy out
+// of linear memory. execute does not set it: it streams its result via Emit
+// and returns nil.
+//export get_last_result_len
+func GetLastResultLen() int32 {
+	return int32(len(lastResult))
+}
+
+// allocs keeps the buffers handed out by Malloc alive until Free is called.
+// TinyGo's GC doesn't know the host is holding a raw pointer into one of
+// these between the malloc call and the execute call that follows it, so
+// without this a collection in between could free the backing array out
+// from under the host's write.
+var allocs = map[*byte][]byte{}
+
+// Malloc allocates size bytes of linear memory for the host to write
+// argument JSON into before calling execute.
+//export malloc
+func Malloc(size int32) *byte {
+	buf := make([]byte, size)
+	if len(buf) == 0 {
+		return nil
+	}
+	allocs[&buf[0]] = buf
+	return &buf[0]
+}
+
+// Free releases a buffer previously returned by Malloc.
+//export free
+func Free(ptr *byte) {
+	delete(allocs, ptr)
+}
+
+// commandArgs is the single source of truth for this plugin's argument
+// schema: GetCommand reports it to the host, and Execute uses the same
+// slice to validate and coerce incoming args via pluginargs.
+var commandArgs = []pluginargs.ArgDefinition{
+	{
+		Name:       "input",
+		Short:      strPtr("i"),
+		Long:       strPtr("input"),
+		Help:       "Input file or value",
+		Required:   false,
+		TakesValue: true,
+		Type:       pluginargs.TypeString,
+		Default:    "default",
+	},
+	{
+		Name:       "verbose",
+		Short:      strPtr("v"),
+		Long:       strPtr("verbose"),
+		Help:       "Enable verbose output",
+		Required:   false,
+		TakesValue: false,
+		Type:       pluginargs.TypeBool,
+	},
+	// TODO: Add more arguments as needed
+}
+
+func strPtr(s string) *string { return &s }
+
+// hostEmit and hostShouldCancel are the host functions the kargo runtime
+// makes available to every plugin under the "kargo" import module. The
+// go:wasmimport ABI only accepts numeric types and unsafe.Pointer as
+// parameters, hence ptr being unsafe.Pointer rather than *byte here.
+//
+//go:wasmimport kargo kargo_emit
+func hostEmit(ptr unsafe.Pointer, length int32)
+
+//go:wasmimport kargo kargo_should_cancel
+func hostShouldCancel() int32
+
+// Emit sends one event of the streaming execution ABI to the host: kind is
+// "log", "progress", or "result", and fields carries that event's payload
+// (e.g. {"level":"info","msg":...}, {"pct":42}, or
+// {"success":true,"output":...}). A "result" event is terminal.
+func Emit(kind string, fields map[string]interface{}) {
+	fields["kind"] = kind
+	data, err := json.Marshal(fields)
+	if err != nil || len(data) == 0 {
+		return
+	}
+	hostEmit(unsafe.Pointer(&data[0]), int32(len(data)))
+}
+
+// Cancelled reports whether the host has asked this execution to stop
+// early, e.g. because its timeout elapsed. Long-running plugins should
+// poll this between units of work.
+func Cancelled() bool {
+	return hostShouldCancel() != 0
+}
+
 // GetCommand returns the command definition
 //export get_command
 func GetCommand() *byte {
-	shortI := "i"
-	longI := "input"
-	shortV := "v"
-	longV := "verbose"
-	
 	cmd := CommandDefinition{
 		Name:  "{{plugin_name}}",
 		About: "{{plugin_description}}",
-		Args: []ArgDefinition{
-			{
-				Name:       "input",
-				Short:      &shortI,
-				Long:       &longI,
-				Help:       "Input file or value",
-				Required:   false,
-				TakesValue: true,
-			},
-			{
-				Name:       "verbose",
-				Short:      &shortV,
-				Long:       &longV,
-				Help:       "Enable verbose output",
-				Required:   false,
-				TakesValue: false,
-			},
-			// TODO: Add more arguments as needed
-		},
+		Args:  commandArgs,
 	}
-	
+
 	data, _ := json.Marshal(cmd)
-	return &data[0]
+	return setResult(data)
 }
 
-// Execute runs the plugin with given arguments
+// Execute runs the plugin with given arguments. Rather than returning a
+// single terminal blob, it streams log/progress events via Emit and
+// finishes with a terminal "result" event; the return value is unused by
+// the host (kept as *byte only so the export signature matches the other
+// exports) and is always nil.
 //export execute
 func Execute(argsPtr *byte, argsLen int) *byte {
 	// Convert args from WASM memory
@@ -86,37 +191,34 @@ func Execute(argsPtr *byte, argsLen int) *byte {
 	for i := 0; i < argsLen; i++ {
 		args[i] = *(*byte)(unsafe.Pointer(uintptr(unsafe.Pointer(argsPtr)) + uintptr(i)))
 	}
-	
+
 	var argsMap map[string]interface{}
 	if err := json.Unmarshal(args, &argsMap); err != nil {
-		errStr := err.Error()
-		result := ExecutionResult{
-			Success: false,
-			Error:   &errStr,
-		}
-		data, _ := json.Marshal(result)
-		return &data[0]
+		Emit("result", map[string]interface{}{"success": false, "error": err.Error()})
+		return nil
 	}
-	
-	// TODO: Implement your plugin logic here
-	input, _ := argsMap["input"].(string)
-	if input == "" {
-		input = "default"
+
+	var parsed struct {
+		Input   string `json:"input"`
+		Verbose bool   `json:"verbose"`
 	}
-	
-	output := fmt.Sprintf("Hello from {{plugin_name}}! Processing: %s", input)
-	
-	if verbose, ok := argsMap["verbose"].(bool); ok && verbose {
-		output += "\n[Verbose mode enabled]"
+	if err := pluginargs.Decode(commandArgs, argsMap, &parsed); err != nil {
+		Emit("result", map[string]interface{}{"success": false, "error": err.Error()})
+		return nil
 	}
-	
-	result := ExecutionResult{
-		Success: true,
-		Output:  &output,
+
+	Emit("log", map[string]interface{}{"level": "info", "msg": fmt.Sprintf("processing %s", parsed.Input)})
+	Emit("progress", map[string]interface{}{"pct": 50})
+
+	// TODO: Implement your plugin logic here
+	output := fmt.Sprintf("Hello from {{plugin_name}}! Processing: %s", parsed.Input)
+	if parsed.Verbose {
+		output += "\n[Verbose mode enabled]"
 	}
-	
-	data, _ := json.Marshal(result)
-	return &data[0]
+
+	Emit("progress", map[string]interface{}{"pct": 100})
+	Emit("result", map[string]interface{}{"success": true, "output": output})
+	return nil
 }
 
 // GetMetadata returns plugin metadata
@@ -129,9 +231,9 @@ func GetMetadata() *byte {
 		Author:      "{{author_name}}",
 		Language:    "go",
 	}
-	
+
 	data, _ := json.Marshal(metadata)
-	return &data[0]
+	return setResult(data)
 }
 
 // Required for TinyGo WASM